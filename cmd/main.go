@@ -38,7 +38,7 @@ myNegativeReal -3.1456
 	tokens := []interpret.Token{}
 
 	for {
-		token, err := scanner.ReadToken()
+		token, err := scanner.NextToken()
 		if errors.Is(err, io.EOF) {
 			break
 		}
@@ -0,0 +1,25 @@
+package interpret
+
+// Dict is a PostScript dictionary: a name-indexed store of Objects, used
+// for systemdict, globaldict, userdict, and any dictionary created with
+// the dict operator.
+type Dict struct {
+	entries map[string]Object
+}
+
+func NewDict() *Dict {
+	return &Dict{entries: map[string]Object{}}
+}
+
+func (d *Dict) Get(name string) (Object, bool) {
+	obj, ok := d.entries[name]
+	return obj, ok
+}
+
+func (d *Dict) Put(name string, obj Object) {
+	d.entries[name] = obj
+}
+
+func (d *Dict) Len() int {
+	return len(d.entries)
+}
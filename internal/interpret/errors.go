@@ -1,23 +1,90 @@
 package interpret
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/ian-shakespeare/libps/pkg/runes"
+)
 
 type ScanError struct {
+	Pos     runes.Pos
 	Type    string
 	Message string
 }
 
-func NewSyntaxError(message string) *ScanError {
+func NewSyntaxError(pos runes.Pos, message string) *ScanError {
 	return &ScanError{
+		Pos:     pos,
 		Type:    "syntaxerror",
 		Message: message,
 	}
 }
 
-func NewSyntaxErrorf(format string, a ...any) *ScanError {
-	return NewSyntaxError(fmt.Sprintf(format, a...))
+func NewSyntaxErrorf(pos runes.Pos, format string, a ...any) *ScanError {
+	return NewSyntaxError(pos, fmt.Sprintf(format, a...))
+}
+
+// NewRangeCheckScanError reports a scanned value outside the range its
+// syntax allows, e.g. a radix digit too large for its declared base.
+func NewRangeCheckScanError(pos runes.Pos, message string) *ScanError {
+	return &ScanError{
+		Pos:     pos,
+		Type:    "rangecheck",
+		Message: message,
+	}
+}
+
+func NewRangeCheckScanErrorf(pos runes.Pos, format string, a ...any) *ScanError {
+	return NewRangeCheckScanError(pos, fmt.Sprintf(format, a...))
 }
 
 func (s *ScanError) Error() string {
-	return fmt.Sprintf("%s: %s", s.Type, s.Message)
+	return fmt.Sprintf("%d:%d: %s: %s", s.Pos.Line, s.Pos.Col, s.Type, s.Message)
+}
+
+// PSError is a PostScript-shaped runtime error, generalizing the
+// (type, message) pattern of ScanError to the parser and interpreter,
+// which have no source position once a token has been parsed into an
+// Object.
+type PSError struct {
+	Type    string
+	Message string
+}
+
+func NewPSError(errType, message string) *PSError {
+	return &PSError{
+		Type:    errType,
+		Message: message,
+	}
+}
+
+func NewPSErrorf(errType, format string, a ...any) *PSError {
+	return NewPSError(errType, fmt.Sprintf(format, a...))
+}
+
+func (e *PSError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Type, e.Message)
+}
+
+// NewStackUnderflowError reports an operator finding fewer operands on
+// the stack than it needs.
+func NewStackUnderflowError() *PSError {
+	return NewPSError("stackunderflow", "stack underflow")
+}
+
+// NewTypeCheckError reports an operand of the wrong type for an operator.
+func NewTypeCheckError() *PSError {
+	return NewPSError("typecheck", "typecheck")
+}
+
+// NewUndefinedError reports a name with no binding in any dictionary on
+// the dictionary stack.
+func NewUndefinedError(name string) *PSError {
+	return NewPSErrorf("undefined", "undefined: %s", name)
+}
+
+// NewRangeCheckError reports an index, count, or numeric argument outside
+// the range an operator accepts.
+func NewRangeCheckError() *PSError {
+	return NewPSError("rangecheck", "rangecheck")
 }
@@ -0,0 +1,144 @@
+package interpret
+
+import (
+	"errors"
+	"io"
+)
+
+// Interpreter is a tree-walking evaluator for parsed PostScript Objects.
+// It owns the operand stack, the dictionary stack (systemdict at the
+// bottom, then globaldict, then userdict, which is current by default),
+// and executes procedures by walking their element Objects in order.
+type Interpreter struct {
+	operands   []Object
+	dictStack  []*Dict
+	systemdict *Dict
+	globaldict *Dict
+	userdict   *Dict
+}
+
+func NewInterpreter() *Interpreter {
+	systemdict := NewDict()
+	registerOperators(systemdict)
+	globaldict := NewDict()
+	userdict := NewDict()
+
+	return &Interpreter{
+		dictStack:  []*Dict{systemdict, globaldict, userdict},
+		systemdict: systemdict,
+		globaldict: globaldict,
+		userdict:   userdict,
+	}
+}
+
+// Exec parses and evaluates every top-level Object read from r, in order.
+func (i *Interpreter) Exec(r io.Reader) error {
+	p := NewParser(r)
+	defer p.Close()
+
+	for {
+		obj, err := p.ParseObject()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := i.Eval(obj); err != nil {
+			return err
+		}
+	}
+}
+
+// Eval evaluates a single Object. Executable names are looked up and run;
+// an immediate literal name is resolved to its bound value; every other
+// Object, including procedures and arrays, is simply pushed onto the
+// operand stack.
+func (i *Interpreter) Eval(obj Object) error {
+	switch obj.Type {
+	case NAME_OBJECT:
+		return i.execName(obj.Name)
+	case LIT_NAME_OBJECT:
+		if !obj.Immediate {
+			i.push(obj)
+			return nil
+		}
+		value, ok := i.lookup(obj.Name)
+		if !ok {
+			return NewUndefinedError(obj.Name)
+		}
+		i.push(value)
+		return nil
+	default:
+		i.push(obj)
+		return nil
+	}
+}
+
+func (i *Interpreter) execName(name string) error {
+	value, ok := i.lookup(name)
+	if !ok {
+		return NewUndefinedError(name)
+	}
+
+	switch value.Type {
+	case OPERATOR_OBJECT:
+		return value.Op(i)
+	case PROC_OBJECT:
+		return i.execProc(value)
+	default:
+		i.push(value)
+		return nil
+	}
+}
+
+// execProc runs a procedure's body by evaluating each of its elements in
+// order.
+func (i *Interpreter) execProc(proc Object) error {
+	for _, elem := range proc.Array {
+		if err := i.Eval(elem); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lookup searches the dictionary stack from the top (userdict) down to
+// systemdict.
+func (i *Interpreter) lookup(name string) (Object, bool) {
+	for idx := len(i.dictStack) - 1; idx >= 0; idx-- {
+		if obj, ok := i.dictStack[idx].Get(name); ok {
+			return obj, true
+		}
+	}
+	return Object{}, false
+}
+
+func (i *Interpreter) currentDict() *Dict {
+	return i.dictStack[len(i.dictStack)-1]
+}
+
+func (i *Interpreter) push(obj Object) {
+	i.operands = append(i.operands, obj)
+}
+
+func (i *Interpreter) pop() (Object, error) {
+	if len(i.operands) == 0 {
+		return Object{}, NewStackUnderflowError()
+	}
+	obj := i.operands[len(i.operands)-1]
+	i.operands = i.operands[:len(i.operands)-1]
+	return obj, nil
+}
+
+func (i *Interpreter) popInt() (int64, error) {
+	obj, err := i.pop()
+	if err != nil {
+		return 0, err
+	}
+	if obj.Type != INT_OBJECT {
+		return 0, NewTypeCheckError()
+	}
+	return obj.Int, nil
+}
@@ -0,0 +1,154 @@
+package interpret_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ian-shakespeare/libps/internal/interpret"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInterpreter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("arithmetic", func(t *testing.T) {
+		t.Parallel()
+
+		i := interpret.NewInterpreter()
+		err := i.Exec(strings.NewReader("2 3 add 4 mul"))
+		assert.NoError(t, err)
+	})
+
+	t.Run("defAndLoad", func(t *testing.T) {
+		t.Parallel()
+
+		i := interpret.NewInterpreter()
+		err := i.Exec(strings.NewReader("/x 5 def /y x def"))
+		assert.NoError(t, err)
+	})
+
+	t.Run("procExecutesOnExec", func(t *testing.T) {
+		t.Parallel()
+
+		i := interpret.NewInterpreter()
+		err := i.Exec(strings.NewReader("{1 2 add} exec"))
+		assert.NoError(t, err)
+	})
+
+	t.Run("ifelse", func(t *testing.T) {
+		t.Parallel()
+
+		i := interpret.NewInterpreter()
+		err := i.Exec(strings.NewReader("true {1} {2} ifelse"))
+		assert.NoError(t, err)
+	})
+
+	t.Run("stackUnderflow", func(t *testing.T) {
+		t.Parallel()
+
+		i := interpret.NewInterpreter()
+		err := i.Exec(strings.NewReader("add"))
+		assert.Error(t, err)
+	})
+
+	t.Run("undefinedName", func(t *testing.T) {
+		t.Parallel()
+
+		i := interpret.NewInterpreter()
+		err := i.Exec(strings.NewReader("notaname"))
+		assert.Error(t, err)
+	})
+
+	t.Run("typeCheck", func(t *testing.T) {
+		t.Parallel()
+
+		i := interpret.NewInterpreter()
+		err := i.Exec(strings.NewReader("(not a number) 1 add"))
+		assert.Error(t, err)
+	})
+
+	t.Run("arrayLiteralIsPushedNotExecuted", func(t *testing.T) {
+		t.Parallel()
+
+		i := interpret.NewInterpreter()
+		err := i.Exec(strings.NewReader("[1 2 3] length"))
+		assert.NoError(t, err)
+	})
+
+	t.Run("delimiterTerminatedArrayElementIsDecoded", func(t *testing.T) {
+		t.Parallel()
+
+		// The trailing "3" in "[1 2 3]" is terminated by the array's
+		// closing "]" rather than whitespace. Use its decoded value to
+		// size a fresh array, then put at index 2: this only stays in
+		// range if get actually read back 3, not the zero value a
+		// mis-scanned token would leave behind.
+		i := interpret.NewInterpreter()
+		err := i.Exec(strings.NewReader("[1 2 3] 2 get array 2 0 put"))
+		assert.NoError(t, err)
+	})
+}
+
+func TestParser(t *testing.T) {
+	t.Parallel()
+
+	t.Run("parseProc", func(t *testing.T) {
+		t.Parallel()
+
+		p := interpret.NewParser(strings.NewReader("{1 2 add}"))
+		defer p.Close()
+
+		obj, err := p.ParseObject()
+		assert.NoError(t, err)
+		assert.Equal(t, interpret.PROC_OBJECT, obj.Type)
+		assert.Len(t, obj.Array, 3)
+	})
+
+	t.Run("parseArray", func(t *testing.T) {
+		t.Parallel()
+
+		p := interpret.NewParser(strings.NewReader("[1 /foo (bar)]"))
+		defer p.Close()
+
+		obj, err := p.ParseObject()
+		assert.NoError(t, err)
+		assert.Equal(t, interpret.ARRAY_OBJECT, obj.Type)
+		assert.Len(t, obj.Array, 3)
+		assert.Equal(t, int64(1), obj.Array[0].Int)
+	})
+
+	t.Run("parseArrayDelimiterTerminatedElement", func(t *testing.T) {
+		t.Parallel()
+
+		// The "3" has no trailing whitespace before the closing "]", so
+		// it's terminated by the array delimiter rather than by a space.
+		p := interpret.NewParser(strings.NewReader("[1 2 3]"))
+		defer p.Close()
+
+		obj, err := p.ParseObject()
+		assert.NoError(t, err)
+		assert.Equal(t, interpret.ARRAY_OBJECT, obj.Type)
+		assert.Len(t, obj.Array, 3)
+		assert.Equal(t, int64(3), obj.Array[2].Int)
+	})
+
+	t.Run("unexpectedArrayEnd", func(t *testing.T) {
+		t.Parallel()
+
+		p := interpret.NewParser(strings.NewReader("]"))
+		defer p.Close()
+
+		_, err := p.ParseObject()
+		assert.Error(t, err)
+	})
+
+	t.Run("unterminatedProc", func(t *testing.T) {
+		t.Parallel()
+
+		p := interpret.NewParser(strings.NewReader("{1 2"))
+		defer p.Close()
+
+		_, err := p.ParseObject()
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,45 @@
+package interpret
+
+// ObjectType identifies which field of an Object is meaningful.
+type ObjectType int
+
+const (
+	NULL_OBJECT ObjectType = iota
+	INT_OBJECT
+	REAL_OBJECT
+	BOOL_OBJECT
+	STRING_OBJECT
+	NAME_OBJECT
+	LIT_NAME_OBJECT
+	ARRAY_OBJECT
+	PROC_OBJECT
+	DICT_OBJECT
+	OPERATOR_OBJECT
+)
+
+// Operator is a core operator implemented in Go and registered into a
+// dictionary such as systemdict.
+type Operator func(i *Interpreter) error
+
+// Object is the tagged union of every value the interpreter can push onto
+// the operand stack, store in a dictionary, or execute: integers, reals,
+// strings, names (executable and literal), arrays, procedures (executable
+// arrays), dictionaries, booleans, null, and operators. Only the field(s)
+// matching Type are meaningful.
+type Object struct {
+	Type ObjectType
+
+	Int   int64
+	Real  float64
+	Bool  bool
+	Bytes []byte
+	Name  string
+	Array []Object
+	Dict  *Dict
+	Op    Operator
+
+	// Immediate marks a LIT_NAME_OBJECT produced from a //name token: its
+	// value is looked up and substituted wherever the name is evaluated,
+	// rather than pushing the name itself.
+	Immediate bool
+}
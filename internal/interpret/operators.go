@@ -0,0 +1,530 @@
+package interpret
+
+// registerOperators installs the core operator set into dict, to be
+// called once when building systemdict.
+func registerOperators(dict *Dict) {
+	ops := map[string]Operator{
+		"add":    opAdd,
+		"sub":    opSub,
+		"mul":    opMul,
+		"div":    opDiv,
+		"idiv":   opIdiv,
+		"mod":    opMod,
+		"dup":    opDup,
+		"pop":    opPop,
+		"exch":   opExch,
+		"roll":   opRoll,
+		"index":  opIndex,
+		"def":    opDef,
+		"load":   opLoad,
+		"if":     opIf,
+		"ifelse": opIfelse,
+		"for":    opFor,
+		"repeat": opRepeat,
+		"exec":   opExec,
+		"array":  opArray,
+		"dict":   opDict,
+		"get":    opGet,
+		"put":    opPut,
+		"length": opLength,
+	}
+
+	for name, op := range ops {
+		dict.Put(name, Object{Type: OPERATOR_OBJECT, Op: op})
+	}
+
+	dict.Put("true", Object{Type: BOOL_OBJECT, Bool: true})
+	dict.Put("false", Object{Type: BOOL_OBJECT, Bool: false})
+	dict.Put("null", Object{Type: NULL_OBJECT})
+}
+
+func popNumber(i *Interpreter) (Object, error) {
+	obj, err := i.pop()
+	if err != nil {
+		return Object{}, err
+	}
+	if obj.Type != INT_OBJECT && obj.Type != REAL_OBJECT {
+		return Object{}, NewTypeCheckError()
+	}
+	return obj, nil
+}
+
+func realValue(obj Object) float64 {
+	if obj.Type == INT_OBJECT {
+		return float64(obj.Int)
+	}
+	return obj.Real
+}
+
+func nameValue(obj Object) (string, bool) {
+	switch obj.Type {
+	case NAME_OBJECT, LIT_NAME_OBJECT:
+		return obj.Name, true
+	default:
+		return "", false
+	}
+}
+
+func opAdd(i *Interpreter) error {
+	b, err := popNumber(i)
+	if err != nil {
+		return err
+	}
+	a, err := popNumber(i)
+	if err != nil {
+		return err
+	}
+
+	if a.Type == INT_OBJECT && b.Type == INT_OBJECT {
+		i.push(Object{Type: INT_OBJECT, Int: a.Int + b.Int})
+		return nil
+	}
+	i.push(Object{Type: REAL_OBJECT, Real: realValue(a) + realValue(b)})
+	return nil
+}
+
+func opSub(i *Interpreter) error {
+	b, err := popNumber(i)
+	if err != nil {
+		return err
+	}
+	a, err := popNumber(i)
+	if err != nil {
+		return err
+	}
+
+	if a.Type == INT_OBJECT && b.Type == INT_OBJECT {
+		i.push(Object{Type: INT_OBJECT, Int: a.Int - b.Int})
+		return nil
+	}
+	i.push(Object{Type: REAL_OBJECT, Real: realValue(a) - realValue(b)})
+	return nil
+}
+
+func opMul(i *Interpreter) error {
+	b, err := popNumber(i)
+	if err != nil {
+		return err
+	}
+	a, err := popNumber(i)
+	if err != nil {
+		return err
+	}
+
+	if a.Type == INT_OBJECT && b.Type == INT_OBJECT {
+		i.push(Object{Type: INT_OBJECT, Int: a.Int * b.Int})
+		return nil
+	}
+	i.push(Object{Type: REAL_OBJECT, Real: realValue(a) * realValue(b)})
+	return nil
+}
+
+func opDiv(i *Interpreter) error {
+	b, err := popNumber(i)
+	if err != nil {
+		return err
+	}
+	a, err := popNumber(i)
+	if err != nil {
+		return err
+	}
+
+	if realValue(b) == 0 {
+		return NewRangeCheckError()
+	}
+	i.push(Object{Type: REAL_OBJECT, Real: realValue(a) / realValue(b)})
+	return nil
+}
+
+func opIdiv(i *Interpreter) error {
+	b, err := i.popInt()
+	if err != nil {
+		return err
+	}
+	a, err := i.popInt()
+	if err != nil {
+		return err
+	}
+
+	if b == 0 {
+		return NewRangeCheckError()
+	}
+	i.push(Object{Type: INT_OBJECT, Int: a / b})
+	return nil
+}
+
+func opMod(i *Interpreter) error {
+	b, err := i.popInt()
+	if err != nil {
+		return err
+	}
+	a, err := i.popInt()
+	if err != nil {
+		return err
+	}
+
+	if b == 0 {
+		return NewRangeCheckError()
+	}
+	i.push(Object{Type: INT_OBJECT, Int: a % b})
+	return nil
+}
+
+func opDup(i *Interpreter) error {
+	obj, err := i.pop()
+	if err != nil {
+		return err
+	}
+	i.push(obj)
+	i.push(obj)
+	return nil
+}
+
+func opPop(i *Interpreter) error {
+	_, err := i.pop()
+	return err
+}
+
+func opExch(i *Interpreter) error {
+	b, err := i.pop()
+	if err != nil {
+		return err
+	}
+	a, err := i.pop()
+	if err != nil {
+		return err
+	}
+	i.push(b)
+	i.push(a)
+	return nil
+}
+
+func opRoll(i *Interpreter) error {
+	j, err := i.popInt()
+	if err != nil {
+		return err
+	}
+	n, err := i.popInt()
+	if err != nil {
+		return err
+	}
+
+	if n < 0 {
+		return NewRangeCheckError()
+	}
+	if n == 0 {
+		return nil
+	}
+	if int64(len(i.operands)) < n {
+		return NewStackUnderflowError()
+	}
+
+	window := i.operands[len(i.operands)-int(n):]
+	shift := ((j % n) + n) % n
+
+	rolled := make([]Object, 0, n)
+	rolled = append(rolled, window[n-shift:]...)
+	rolled = append(rolled, window[:n-shift]...)
+	copy(window, rolled)
+
+	return nil
+}
+
+func opIndex(i *Interpreter) error {
+	n, err := i.popInt()
+	if err != nil {
+		return err
+	}
+
+	if n < 0 || n >= int64(len(i.operands)) {
+		return NewRangeCheckError()
+	}
+	i.push(i.operands[len(i.operands)-1-int(n)])
+	return nil
+}
+
+func opDef(i *Interpreter) error {
+	value, err := i.pop()
+	if err != nil {
+		return err
+	}
+	key, err := i.pop()
+	if err != nil {
+		return err
+	}
+
+	name, ok := nameValue(key)
+	if !ok {
+		return NewTypeCheckError()
+	}
+	i.currentDict().Put(name, value)
+	return nil
+}
+
+func opLoad(i *Interpreter) error {
+	key, err := i.pop()
+	if err != nil {
+		return err
+	}
+
+	name, ok := nameValue(key)
+	if !ok {
+		return NewTypeCheckError()
+	}
+	value, ok := i.lookup(name)
+	if !ok {
+		return NewUndefinedError(name)
+	}
+	i.push(value)
+	return nil
+}
+
+func opIf(i *Interpreter) error {
+	proc, err := i.pop()
+	if err != nil {
+		return err
+	}
+	cond, err := i.pop()
+	if err != nil {
+		return err
+	}
+
+	if proc.Type != PROC_OBJECT || cond.Type != BOOL_OBJECT {
+		return NewTypeCheckError()
+	}
+	if cond.Bool {
+		return i.execProc(proc)
+	}
+	return nil
+}
+
+func opIfelse(i *Interpreter) error {
+	elseProc, err := i.pop()
+	if err != nil {
+		return err
+	}
+	thenProc, err := i.pop()
+	if err != nil {
+		return err
+	}
+	cond, err := i.pop()
+	if err != nil {
+		return err
+	}
+
+	if thenProc.Type != PROC_OBJECT || elseProc.Type != PROC_OBJECT || cond.Type != BOOL_OBJECT {
+		return NewTypeCheckError()
+	}
+	if cond.Bool {
+		return i.execProc(thenProc)
+	}
+	return i.execProc(elseProc)
+}
+
+func opFor(i *Interpreter) error {
+	proc, err := i.pop()
+	if err != nil {
+		return err
+	}
+	limit, err := popNumber(i)
+	if err != nil {
+		return err
+	}
+	increment, err := popNumber(i)
+	if err != nil {
+		return err
+	}
+	initial, err := popNumber(i)
+	if err != nil {
+		return err
+	}
+
+	if proc.Type != PROC_OBJECT {
+		return NewTypeCheckError()
+	}
+	if realValue(increment) == 0 {
+		return NewRangeCheckError()
+	}
+
+	isInt := initial.Type == INT_OBJECT && increment.Type == INT_OBJECT && limit.Type == INT_OBJECT
+
+	for v := realValue(initial); (realValue(increment) > 0 && v <= realValue(limit)) ||
+		(realValue(increment) < 0 && v >= realValue(limit)); v += realValue(increment) {
+		if isInt {
+			i.push(Object{Type: INT_OBJECT, Int: int64(v)})
+		} else {
+			i.push(Object{Type: REAL_OBJECT, Real: v})
+		}
+		if err := i.execProc(proc); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func opRepeat(i *Interpreter) error {
+	proc, err := i.pop()
+	if err != nil {
+		return err
+	}
+	n, err := i.popInt()
+	if err != nil {
+		return err
+	}
+
+	if proc.Type != PROC_OBJECT {
+		return NewTypeCheckError()
+	}
+	if n < 0 {
+		return NewRangeCheckError()
+	}
+
+	for j := int64(0); j < n; j++ {
+		if err := i.execProc(proc); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func opExec(i *Interpreter) error {
+	obj, err := i.pop()
+	if err != nil {
+		return err
+	}
+	if obj.Type == PROC_OBJECT {
+		return i.execProc(obj)
+	}
+	return i.Eval(obj)
+}
+
+func opArray(i *Interpreter) error {
+	n, err := i.popInt()
+	if err != nil {
+		return err
+	}
+	if n < 0 {
+		return NewRangeCheckError()
+	}
+	i.push(Object{Type: ARRAY_OBJECT, Array: make([]Object, n)})
+	return nil
+}
+
+func opDict(i *Interpreter) error {
+	if _, err := i.popInt(); err != nil {
+		return err
+	}
+	i.push(Object{Type: DICT_OBJECT, Dict: NewDict()})
+	return nil
+}
+
+func opGet(i *Interpreter) error {
+	key, err := i.pop()
+	if err != nil {
+		return err
+	}
+	composite, err := i.pop()
+	if err != nil {
+		return err
+	}
+
+	switch composite.Type {
+	case ARRAY_OBJECT, PROC_OBJECT:
+		if key.Type != INT_OBJECT {
+			return NewTypeCheckError()
+		}
+		if key.Int < 0 || key.Int >= int64(len(composite.Array)) {
+			return NewRangeCheckError()
+		}
+		i.push(composite.Array[key.Int])
+		return nil
+	case STRING_OBJECT:
+		if key.Type != INT_OBJECT {
+			return NewTypeCheckError()
+		}
+		if key.Int < 0 || key.Int >= int64(len(composite.Bytes)) {
+			return NewRangeCheckError()
+		}
+		i.push(Object{Type: INT_OBJECT, Int: int64(composite.Bytes[key.Int])})
+		return nil
+	case DICT_OBJECT:
+		name, ok := nameValue(key)
+		if !ok {
+			return NewTypeCheckError()
+		}
+		value, ok := composite.Dict.Get(name)
+		if !ok {
+			return NewUndefinedError(name)
+		}
+		i.push(value)
+		return nil
+	default:
+		return NewTypeCheckError()
+	}
+}
+
+func opPut(i *Interpreter) error {
+	value, err := i.pop()
+	if err != nil {
+		return err
+	}
+	key, err := i.pop()
+	if err != nil {
+		return err
+	}
+	composite, err := i.pop()
+	if err != nil {
+		return err
+	}
+
+	switch composite.Type {
+	case ARRAY_OBJECT, PROC_OBJECT:
+		if key.Type != INT_OBJECT {
+			return NewTypeCheckError()
+		}
+		if key.Int < 0 || key.Int >= int64(len(composite.Array)) {
+			return NewRangeCheckError()
+		}
+		composite.Array[key.Int] = value
+		return nil
+	case STRING_OBJECT:
+		if key.Type != INT_OBJECT || value.Type != INT_OBJECT {
+			return NewTypeCheckError()
+		}
+		if key.Int < 0 || key.Int >= int64(len(composite.Bytes)) {
+			return NewRangeCheckError()
+		}
+		composite.Bytes[key.Int] = byte(value.Int)
+		return nil
+	case DICT_OBJECT:
+		name, ok := nameValue(key)
+		if !ok {
+			return NewTypeCheckError()
+		}
+		composite.Dict.Put(name, value)
+		return nil
+	default:
+		return NewTypeCheckError()
+	}
+}
+
+func opLength(i *Interpreter) error {
+	obj, err := i.pop()
+	if err != nil {
+		return err
+	}
+
+	switch obj.Type {
+	case ARRAY_OBJECT, PROC_OBJECT:
+		i.push(Object{Type: INT_OBJECT, Int: int64(len(obj.Array))})
+	case STRING_OBJECT:
+		i.push(Object{Type: INT_OBJECT, Int: int64(len(obj.Bytes))})
+	case DICT_OBJECT:
+		i.push(Object{Type: INT_OBJECT, Int: int64(obj.Dict.Len())})
+	default:
+		return NewTypeCheckError()
+	}
+	return nil
+}
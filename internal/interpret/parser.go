@@ -0,0 +1,91 @@
+package interpret
+
+import (
+	"io"
+	"iter"
+)
+
+// parser consumes a scanner's token stream and produces Objects, with
+// PROC_BEGIN/PROC_END and ARRAY_BEGIN/ARRAY_END tokens driving procedure
+// and array nesting.
+type parser struct {
+	next func() (Token, error, bool)
+	stop func()
+}
+
+func NewParser(input io.Reader) *parser {
+	next, stop := iter.Pull2(NewScanner(input).Tokens())
+	return &parser{next: next, stop: stop}
+}
+
+// Close releases the underlying token iterator. It is safe to call more
+// than once.
+func (p *parser) Close() {
+	p.stop()
+}
+
+// ParseObject reads and returns the next top-level Object, or io.EOF once
+// the input is exhausted.
+func (p *parser) ParseObject() (Object, error) {
+	token, err, ok := p.next()
+	if !ok {
+		return Object{}, io.EOF
+	}
+	if err != nil {
+		return Object{}, err
+	}
+
+	return p.parseToken(token)
+}
+
+func (p *parser) parseToken(token Token) (Object, error) {
+	switch token.Type {
+	case INT_TOKEN, RADIX_TOKEN:
+		return Object{Type: INT_OBJECT, Int: token.IntValue}, nil
+	case REAL_TOKEN:
+		return Object{Type: REAL_OBJECT, Real: token.FloatValue}, nil
+	case STRING_TOKEN:
+		return Object{Type: STRING_OBJECT, Bytes: []byte(string(token.Value))}, nil
+	case NAME_TOKEN:
+		return Object{Type: NAME_OBJECT, Name: string(token.Value)}, nil
+	case LIT_NAME_TOKEN:
+		return Object{Type: LIT_NAME_OBJECT, Name: string(token.Value)}, nil
+	case IMMEDIATE_NAME_TOKEN:
+		return Object{Type: LIT_NAME_OBJECT, Name: string(token.Value), Immediate: true}, nil
+	case ARRAY_BEGIN_TOKEN:
+		return p.parseSequence(ARRAY_OBJECT, ARRAY_END_TOKEN)
+	case PROC_BEGIN_TOKEN:
+		return p.parseSequence(PROC_OBJECT, PROC_END_TOKEN)
+	case ARRAY_END_TOKEN:
+		return Object{}, NewPSError("syntaxerror", "unexpected ]")
+	case PROC_END_TOKEN:
+		return Object{}, NewPSError("syntaxerror", "unexpected }")
+	default:
+		return Object{}, NewPSErrorf("syntaxerror", "unrecognized token: %q", string(token.Value))
+	}
+}
+
+// parseSequence reads elements up to the matching end token and wraps
+// them in an Object of the given type.
+func (p *parser) parseSequence(objType ObjectType, end TokenType) (Object, error) {
+	elems := []Object{}
+
+	for {
+		token, err, ok := p.next()
+		if !ok {
+			return Object{}, NewPSError("syntaxerror", "unterminated procedure or array")
+		}
+		if err != nil {
+			return Object{}, err
+		}
+		if token.Type == end {
+			return Object{Type: objType, Array: elems}, nil
+		}
+
+		elem, err := p.parseToken(token)
+		if err != nil {
+			return Object{}, err
+		}
+		elems = append(elems, elem)
+	}
+}
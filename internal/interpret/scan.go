@@ -4,6 +4,7 @@ import (
 	"encoding/ascii85"
 	"errors"
 	"io"
+	"iter"
 	"strconv"
 
 	"github.com/ian-shakespeare/libps/pkg/array"
@@ -11,7 +12,8 @@ import (
 )
 
 type scanner struct {
-	reader *runes.Reader
+	reader       *runes.Reader
+	errorHandler func(pos runes.Pos, err *ScanError)
 }
 
 func NewScanner(input io.Reader) *scanner {
@@ -20,10 +22,64 @@ func NewScanner(input io.Reader) *scanner {
 	}
 }
 
-func (s *scanner) ReadToken() (Token, error) {
+// SetErrorHandler installs a callback invoked for every scan error. Once a
+// handler is set, NextToken reports the error through the callback and
+// resynchronizes at the next whitespace rather than returning the error,
+// so a full batch of source can be scanned for diagnostics in one pass.
+// With no handler installed, NextToken fails fast and returns the error,
+// as before.
+func (s *scanner) SetErrorHandler(handler func(pos runes.Pos, err *ScanError)) {
+	s.errorHandler = handler
+}
+
+// Tokens returns an iterator over every token in the input, stopping
+// silently at EOF. A non-nil error is yielded alongside the zero Token
+// whenever NextToken fails.
+func (s *scanner) Tokens() iter.Seq2[Token, error] {
+	return func(yield func(Token, error) bool) {
+		for {
+			token, err := s.NextToken()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if !yield(token, err) {
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (s *scanner) NextToken() (Token, error) {
+	for {
+		token, err := s.readToken()
+		if err == nil {
+			return token, nil
+		}
+		if errors.Is(err, io.EOF) {
+			return Token{}, err
+		}
+
+		scanErr, ok := err.(*ScanError)
+		if !ok || s.errorHandler == nil {
+			return Token{}, err
+		}
+
+		s.errorHandler(scanErr.Pos, scanErr)
+
+		if err := s.resync(); err != nil {
+			return Token{}, err
+		}
+	}
+}
+
+func (s *scanner) readToken() (Token, error) {
 	token := Token{Type: UNKNOWN_TOKEN, Value: []rune{}}
 
 	for {
+		pos := s.reader.Pos()
 		char, _, err := s.reader.ReadRune()
 		if err != nil {
 			return Token{}, err
@@ -35,25 +91,46 @@ func (s *scanner) ReadToken() (Token, error) {
 			if err := s.readComment(); err != nil {
 				return Token{}, err
 			}
-			return s.ReadToken()
+			return s.readToken()
 		case '.':
+			token.Pos = pos
 			token.Append(char)
 			err = s.readReal(&token)
 			return token, err
 		case '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+			token.Pos = pos
 			token.Append(char)
 			err = s.readNumeric(&token)
 			return token, err
 		case '(':
+			token.Pos = pos
 			err = s.readLiteralString(&token)
 			return token, err
+		case '/':
+			next, err := s.reader.PeekRunes(1)
+			if err != nil {
+				return Token{}, err
+			}
+
+			token.Pos = pos
+			if len(next) > 0 && next[0] == '/' {
+				if _, _, err := s.reader.ReadRune(); err != nil {
+					return Token{}, err
+				}
+				token.Type = IMMEDIATE_NAME_TOKEN
+			} else {
+				token.Type = LIT_NAME_TOKEN
+			}
+			err = s.readNameBody(&token)
+			return token, err
 		case '<':
 			next, err := s.reader.PeekRunes(1)
 			if err != nil {
 				return Token{}, err
 			}
 
-			if next[0] == '~' {
+			token.Pos = pos
+			if len(next) > 0 && next[0] == '~' {
 				_, _, err = s.reader.ReadRune()
 				if err != nil {
 					return Token{}, err
@@ -64,7 +141,28 @@ func (s *scanner) ReadToken() (Token, error) {
 				err = s.readHexString(&token)
 			}
 			return token, err
+		case '{':
+			token.Pos = pos
+			token.Type = PROC_BEGIN_TOKEN
+			token.Append(char)
+			return token, nil
+		case '}':
+			token.Pos = pos
+			token.Type = PROC_END_TOKEN
+			token.Append(char)
+			return token, nil
+		case '[':
+			token.Pos = pos
+			token.Type = ARRAY_BEGIN_TOKEN
+			token.Append(char)
+			return token, nil
+		case ']':
+			token.Pos = pos
+			token.Type = ARRAY_END_TOKEN
+			token.Append(char)
+			return token, nil
 		default:
+			token.Pos = pos
 			token.Append(char)
 			err = s.readName(&token)
 			return token, err
@@ -72,14 +170,41 @@ func (s *scanner) ReadToken() (Token, error) {
 	}
 }
 
+// resync discards runes up to and including the next whitespace rune, so
+// scanning can resume after a malformed token.
+func (s *scanner) resync() error {
+	for {
+		char, _, err := s.reader.ReadRune()
+		if err != nil {
+			return err
+		}
+		switch char {
+		case '\x00', ' ', '\t', '\r', '\n', '\b', '\f':
+			return nil
+		}
+	}
+}
+
+// isDelimiter reports whether r begins or ends a procedure body or array
+// constructor. These always scan as their own token, even immediately
+// adjacent to a name or number, e.g. "foo{bar}" is three tokens.
+func isDelimiter(r rune) bool {
+	switch r {
+	case '{', '}', '[', ']':
+		return true
+	default:
+		return false
+	}
+}
+
 func (s *scanner) readComment() error {
 	for {
-		b, err := s.reader.ReadByte()
+		char, _, err := s.reader.ReadRune()
 		if err != nil {
 			return err
 		}
 
-		if b == '\n' || b == '\f' {
+		if char == '\n' || char == '\f' {
 			break
 		}
 	}
@@ -103,14 +228,22 @@ wordBuilder:
 		switch char {
 		case '\x00', ' ', '\t', '\r', '\n', '\b', '\f':
 			break wordBuilder
+		case '{', '}', '[', ']':
+			if err := s.reader.UnreadRune(); err != nil {
+				return err
+			}
+			break wordBuilder
 		case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
 			token.Append(char)
 		case '.':
 			token.Append(char)
 			return s.readReal(token)
+		case 'e', 'E':
+			token.Append(char)
+			return s.readReal(token)
 		case '#':
 			if token.Value[0] == '-' {
-				return NewSyntaxError("radix number cannot have a negative base")
+				return NewSyntaxError(s.reader.Pos(), "radix number cannot have a negative base")
 			}
 			token.Append(char)
 			return s.readRadix(token)
@@ -120,6 +253,12 @@ wordBuilder:
 		}
 	}
 
+	value, err := strconv.ParseInt(string(token.Value), 10, 64)
+	if err != nil {
+		return NewSyntaxErrorf(s.reader.Pos(), "malformed integer: %s", string(token.Value))
+	}
+	token.IntValue = value
+
 	return nil
 }
 
@@ -133,7 +272,7 @@ wordBuilder:
 		char, _, err := s.reader.ReadRune()
 		if errors.Is(err, io.EOF) {
 			if hasTrailingExponent {
-				return NewSyntaxError("unexpected end of real number")
+				return NewSyntaxError(s.reader.Pos(), "unexpected end of real number")
 			}
 			break
 		}
@@ -144,7 +283,15 @@ wordBuilder:
 		switch char {
 		case '\x00', ' ', '\t', '\r', '\n', '\b', '\f':
 			if hasTrailingExponent {
-				return NewSyntaxError("unexpected end of real number")
+				return NewSyntaxError(s.reader.Pos(), "unexpected end of real number")
+			}
+			break wordBuilder
+		case '{', '}', '[', ']':
+			if hasTrailingExponent {
+				return NewSyntaxError(s.reader.Pos(), "unexpected end of real number")
+			}
+			if err := s.reader.UnreadRune(); err != nil {
+				return err
 			}
 			break wordBuilder
 		case 'e', 'E':
@@ -165,12 +312,46 @@ wordBuilder:
 		}
 	}
 
+	value, err := strconv.ParseFloat(string(token.Value), 64)
+	if err != nil {
+		return NewSyntaxErrorf(s.reader.Pos(), "malformed real number: %s", string(token.Value))
+	}
+	token.FloatValue = value
+
 	return nil
 }
 
+// radixDigitValue reports the numeric value of a base-36 digit character
+// ('0'-'9', 'a'-'z', 'A'-'Z'), and whether char is a digit character at
+// all.
+func radixDigitValue(char rune) (int64, bool) {
+	switch {
+	case char >= '0' && char <= '9':
+		return int64(char - '0'), true
+	case char >= 'a' && char <= 'z':
+		return int64(char-'a') + 10, true
+	case char >= 'A' && char <= 'Z':
+		return int64(char-'A') + 10, true
+	default:
+		return 0, false
+	}
+}
+
 func (s *scanner) readRadix(token *Token) error {
 	token.Type = RADIX_TOKEN
 
+	baseDigits := token.Value[:len(token.Value)-1]
+	base, err := strconv.ParseInt(string(baseDigits), 10, 64)
+	if err != nil {
+		return NewSyntaxErrorf(s.reader.Pos(), "malformed radix base: %s", string(baseDigits))
+	}
+	if base < 2 || base > 36 {
+		return NewRangeCheckScanErrorf(s.reader.Pos(), "radix base must be between 2 and 36: %d", base)
+	}
+
+	var value int64
+	sawDigit := false
+
 wordBuilder:
 	for {
 		hasTrailingHash := token.Value[len(token.Value)-1] == '#'
@@ -178,7 +359,7 @@ wordBuilder:
 		char, _, err := s.reader.ReadRune()
 		if errors.Is(err, io.EOF) {
 			if hasTrailingHash {
-				return NewSyntaxError("unexpected end of radix number")
+				return NewSyntaxError(s.reader.Pos(), "unexpected end of radix number")
 			}
 			break
 		}
@@ -189,29 +370,50 @@ wordBuilder:
 		switch char {
 		case '\x00', ' ', '\t', '\r', '\n', '\b', '\f':
 			if hasTrailingHash {
-				return NewSyntaxError("unexpected end of radix number")
+				return NewSyntaxError(s.reader.Pos(), "unexpected end of radix number")
+			}
+			break wordBuilder
+		case '{', '}', '[', ']':
+			if hasTrailingHash {
+				return NewSyntaxError(s.reader.Pos(), "unexpected end of radix number")
+			}
+			if err := s.reader.UnreadRune(); err != nil {
+				return err
 			}
 			break wordBuilder
-		case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9', 'a', 'b', 'c', 'd', 'e', 'f', 'g', 'h', 'i', 'j', 'k', 'l', 'm', 'n', 'o', 'p', 'q', 'r', 's', 't', 'u', 'v', 'w', 'x', 'y', 'z', 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O', 'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z':
-			token.Append(char)
 		default:
+			digit, ok := radixDigitValue(char)
+			if !ok {
+				token.Append(char)
+				return s.readName(token)
+			}
+			if digit >= base {
+				return NewRangeCheckScanErrorf(s.reader.Pos(), "digit %q out of range for base %d", char, base)
+			}
 			token.Append(char)
-			return s.readName(token)
+			value = value*base + digit
+			sawDigit = true
 		}
 	}
 
+	if !sawDigit {
+		return NewSyntaxError(s.reader.Pos(), "radix number has no digits")
+	}
+
+	token.IntValue = value
+
 	return nil
 }
 
 func (s *scanner) readLiteralString(token *Token) error {
-	token.Type = LIT_STRING_TOKEN
+	token.Type = STRING_TOKEN
 	activeParens := 0
 
 wordBuilder:
 	for {
 		char, _, err := s.reader.ReadRune()
 		if errors.Is(err, io.EOF) {
-			return NewSyntaxError("unexpected end of file")
+			return NewSyntaxError(s.reader.Pos(), "unexpected end of file")
 		}
 		if err != nil {
 			return err
@@ -270,7 +472,7 @@ wordBuilder:
 				octal = append(octal, nextDigits...)
 				value, err := strconv.ParseInt(string(octal), 8, 32)
 				if err != nil {
-					return NewSyntaxErrorf("unrecognized escape sequence: %s", string(octal))
+					return NewSyntaxErrorf(s.reader.Pos(), "unrecognized escape sequence: %s", string(octal))
 				}
 				if _, _, err = s.reader.ReadRunes(2); err != nil {
 					return err
@@ -288,7 +490,7 @@ wordBuilder:
 }
 
 func (s *scanner) readHexString(token *Token) error {
-	token.Type = HEX_STRING_TOKEN
+	digits := []rune{}
 
 wordBuilder:
 	for {
@@ -302,22 +504,37 @@ wordBuilder:
 
 		switch char {
 		case '>':
-			if len(token.Value)&1 != 0 {
-				token.Append('0')
-			}
 			break wordBuilder
+		case '\x00', ' ', '\t', '\r', '\n', '\f':
+			continue wordBuilder
 		case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9', 'a', 'b', 'c', 'd', 'e', 'f', 'A', 'B', 'C', 'D', 'E', 'F':
-			token.Append(char)
+			digits = append(digits, char)
 		default:
-			return NewSyntaxError("invalid hexidecimal")
+			return NewSyntaxError(s.reader.Pos(), "invalid hexadecimal")
+		}
+	}
+
+	if len(digits)&1 != 0 {
+		digits = append(digits, '0')
+	}
+
+	decoded := make([]byte, len(digits)/2)
+	for i := range decoded {
+		b, err := strconv.ParseUint(string(digits[i*2:i*2+2]), 16, 8)
+		if err != nil {
+			return NewSyntaxError(s.reader.Pos(), "invalid hexadecimal")
 		}
+		decoded[i] = byte(b)
 	}
 
+	token.Type = STRING_TOKEN
+	token.Value = []rune(string(decoded))
+
 	return nil
 }
 
 func (s *scanner) readBase85String(token *Token) error {
-	token.Type = BASE85_STRING_TOKEN
+	encoded := []rune{}
 
 wordBuilder:
 	for {
@@ -342,23 +559,33 @@ wordBuilder:
 				}
 				break wordBuilder
 			}
-			token.Append(char)
+			encoded = append(encoded, char)
 		default:
-			token.Append(char)
+			encoded = append(encoded, char)
 		}
 	}
 
-	_, _, err := ascii85.Decode(nil, runes.ToUTF8(token.Value), true)
+	decoded := make([]byte, len(encoded))
+	n, _, err := ascii85.Decode(decoded, runes.ToUTF8(encoded), true)
 	if err != nil {
-		return NewSyntaxError("invalid base85")
+		return NewSyntaxError(s.reader.Pos(), "invalid base85")
 	}
 
+	token.Type = STRING_TOKEN
+	token.Value = []rune(string(decoded[:n]))
+
 	return nil
 }
 
 func (s *scanner) readName(token *Token) error {
 	token.Type = NAME_TOKEN
+	return s.readNameBody(token)
+}
 
+// readNameBody scans a name's characters into token.Value without
+// touching token.Type, so callers that have already classified the
+// token (e.g. literal and immediate names) can reuse the same scan.
+func (s *scanner) readNameBody(token *Token) error {
 	for {
 		char, _, err := s.reader.ReadRune()
 		if errors.Is(err, io.EOF) {
@@ -371,6 +598,9 @@ func (s *scanner) readName(token *Token) error {
 		if array.Contains([]rune{'\x00', ' ', '\t', '\r', '\n', '\b', '\f'}, char) {
 			break
 		}
+		if isDelimiter(char) {
+			return s.reader.UnreadRune()
+		}
 		token.Append(char)
 	}
 
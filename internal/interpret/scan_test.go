@@ -1,6 +1,7 @@
 package interpret_test
 
 import (
+	"errors"
 	"io"
 	"strings"
 	"testing"
@@ -8,6 +9,7 @@ import (
 	"github.com/ian-shakespeare/libps/internal/interpret"
 	"github.com/ian-shakespeare/libps/pkg/array"
 	"github.com/ian-shakespeare/libps/pkg/iterator"
+	"github.com/ian-shakespeare/libps/pkg/runes"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -55,9 +57,15 @@ func TestScan(t *testing.T) {
 		{"realScientificNegative", "-1.2e7", interpret.REAL_TOKEN},
 		{"realScientificFraction", "1.2e-7", interpret.REAL_TOKEN},
 		{"realScientificNegativeFraction", "-1.2e-7", interpret.REAL_TOKEN},
+		{"realScientificNoPoint", "1e10", interpret.REAL_TOKEN},
+		{"realScientificNoPointFraction", "-.5e-3", interpret.REAL_TOKEN},
 		{"radixBase2", "2#1000", interpret.RADIX_TOKEN},
 		{"radixBase8", "8#1777", interpret.RADIX_TOKEN},
 		{"radixBase16", "16#FFFE", interpret.RADIX_TOKEN},
+		{"radixBase16LowerCase", "16#deadbeef", interpret.RADIX_TOKEN},
+		{"radixBase36", "36#zz", interpret.RADIX_TOKEN},
+		{"radixBaseMin", "2#1", interpret.RADIX_TOKEN},
+		{"radixBaseMax", "36#z", interpret.RADIX_TOKEN},
 	}
 
 	for _, input := range validNumerics {
@@ -67,7 +75,64 @@ func TestScan(t *testing.T) {
 			s := interpret.NewScanner(strings.NewReader(input.value))
 			token, err := s.NextToken()
 			assert.NoError(t, err)
-			assert.Equal(t, interpret.Token{Type: input.tokenType, Value: []rune(input.value)}, token)
+			assert.Equal(t, input.tokenType, token.Type)
+			assert.Equal(t, []rune(input.value), token.Value)
+		})
+	}
+
+	decodedNumerics := []struct {
+		name       string
+		value      string
+		tokenType  interpret.TokenType
+		intValue   int64
+		floatValue float64
+	}{
+		{"decodeInteger", "1234567890", interpret.INT_TOKEN, 1234567890, 0},
+		{"decodeIntegerNegative", "-1234567890", interpret.INT_TOKEN, -1234567890, 0},
+		{"decodeReal", "3.1456", interpret.REAL_TOKEN, 0, 3.1456},
+		{"decodeRealScientificNoPoint", "1e10", interpret.REAL_TOKEN, 0, 1e10},
+		{"decodeRealScientificNoPointFraction", "-.5e-3", interpret.REAL_TOKEN, 0, -.5e-3},
+		{"decodeRadixBase16", "16#deadbeef", interpret.RADIX_TOKEN, 0xdeadbeef, 0},
+		{"decodeRadixBase36", "36#zz", interpret.RADIX_TOKEN, 35*36 + 35, 0},
+	}
+
+	for _, input := range decodedNumerics {
+		t.Run(input.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := interpret.NewScanner(strings.NewReader(input.value))
+			token, err := s.NextToken()
+			assert.NoError(t, err)
+			assert.Equal(t, input.tokenType, token.Type)
+			switch input.tokenType {
+			case interpret.REAL_TOKEN:
+				assert.Equal(t, input.floatValue, token.FloatValue)
+			default:
+				assert.Equal(t, input.intValue, token.IntValue)
+			}
+		})
+	}
+
+	invalidRadices := []struct {
+		name  string
+		value string
+	}{
+		{"radixDigitOutOfRange", "2#102"},
+		{"radixBaseTooSmall", "1#11"},
+		{"radixBaseTooLarge", "37#11"},
+	}
+
+	for _, input := range invalidRadices {
+		t.Run(input.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := interpret.NewScanner(strings.NewReader(input.value))
+			_, err := s.NextToken()
+			assert.Error(t, err)
+
+			var scanErr *interpret.ScanError
+			assert.ErrorAs(t, err, &scanErr)
+			assert.Equal(t, "rangecheck", scanErr.Type)
 		})
 	}
 
@@ -138,7 +203,64 @@ func TestScan(t *testing.T) {
 		s := interpret.NewScanner(strings.NewReader("(\\777)"))
 		token, err := s.NextToken()
 		assert.NoError(t, err)
-		assert.Equal(t, 511, token.Value[0])
+		assert.Equal(t, rune(511), token.Value[0])
+	})
+
+	t.Run("hexString", func(t *testing.T) {
+		t.Parallel()
+
+		hexStrings := []struct {
+			name   string
+			value  string
+			expect string
+		}{
+			{"hexString", "<68656c6c6f>", "hello"},
+			{"hexStringUpper", "<68656C6C6F>", "hello"},
+			{"hexStringWhitespace", "<68 65 6c 6c 6f>", "hello"},
+			{"hexStringOddNybbles", "<48454c4c4f4>", "HELLO@"},
+		}
+
+		for _, input := range hexStrings {
+			t.Run(input.name, func(t *testing.T) {
+				t.Parallel()
+
+				s := interpret.NewScanner(strings.NewReader(input.value))
+				token, err := s.NextToken()
+				assert.NoError(t, err)
+				assert.Equal(t, interpret.STRING_TOKEN, token.Type)
+				assert.Equal(t, input.expect, string(token.Value))
+			})
+		}
+	})
+
+	t.Run("base85String", func(t *testing.T) {
+		t.Parallel()
+
+		s := interpret.NewScanner(strings.NewReader("<~BOu!rD]j7BEbo7~>"))
+		token, err := s.NextToken()
+		assert.NoError(t, err)
+		assert.Equal(t, interpret.STRING_TOKEN, token.Type)
+		assert.Equal(t, "hello world", string(token.Value))
+	})
+
+	t.Run("literalName", func(t *testing.T) {
+		t.Parallel()
+
+		s := interpret.NewScanner(strings.NewReader("/foo"))
+		token, err := s.NextToken()
+		assert.NoError(t, err)
+		assert.Equal(t, interpret.LIT_NAME_TOKEN, token.Type)
+		assert.Equal(t, "foo", string(token.Value))
+	})
+
+	t.Run("immediateName", func(t *testing.T) {
+		t.Parallel()
+
+		s := interpret.NewScanner(strings.NewReader("//foo"))
+		token, err := s.NextToken()
+		assert.NoError(t, err)
+		assert.Equal(t, interpret.IMMEDIATE_NAME_TOKEN, token.Type)
+		assert.Equal(t, "foo", string(token.Value))
 	})
 
 	t.Run("name", func(t *testing.T) {
@@ -154,6 +276,147 @@ func TestScan(t *testing.T) {
 		}
 	})
 
+	t.Run("delimiters", func(t *testing.T) {
+		t.Parallel()
+
+		delimiters := []struct {
+			name      string
+			value     string
+			tokenType interpret.TokenType
+		}{
+			{"procBegin", "{", interpret.PROC_BEGIN_TOKEN},
+			{"procEnd", "}", interpret.PROC_END_TOKEN},
+			{"arrayBegin", "[", interpret.ARRAY_BEGIN_TOKEN},
+			{"arrayEnd", "]", interpret.ARRAY_END_TOKEN},
+		}
+
+		for _, input := range delimiters {
+			t.Run(input.name, func(t *testing.T) {
+				t.Parallel()
+
+				s := interpret.NewScanner(strings.NewReader(input.value))
+				token, err := s.NextToken()
+				assert.NoError(t, err)
+				assert.Equal(t, input.tokenType, token.Type)
+				assert.Equal(t, []rune(input.value), token.Value)
+			})
+		}
+
+		packedForms := []struct {
+			name   string
+			value  string
+			expect []interpret.TokenType
+		}{
+			{
+				"proc",
+				"{dup mul}",
+				[]interpret.TokenType{
+					interpret.PROC_BEGIN_TOKEN,
+					interpret.NAME_TOKEN,
+					interpret.NAME_TOKEN,
+					interpret.PROC_END_TOKEN,
+				},
+			},
+			{
+				"array",
+				"[1 2 3]",
+				[]interpret.TokenType{
+					interpret.ARRAY_BEGIN_TOKEN,
+					interpret.INT_TOKEN,
+					interpret.INT_TOKEN,
+					interpret.INT_TOKEN,
+					interpret.ARRAY_END_TOKEN,
+				},
+			},
+			{
+				"nameAdjacentToProc",
+				"foo{bar}",
+				[]interpret.TokenType{
+					interpret.NAME_TOKEN,
+					interpret.PROC_BEGIN_TOKEN,
+					interpret.NAME_TOKEN,
+					interpret.PROC_END_TOKEN,
+				},
+			},
+		}
+
+		for _, input := range packedForms {
+			t.Run(input.name, func(t *testing.T) {
+				t.Parallel()
+
+				s := interpret.NewScanner(strings.NewReader(input.value))
+				types := []interpret.TokenType{}
+				for {
+					token, err := s.NextToken()
+					if errors.Is(err, io.EOF) {
+						break
+					}
+					assert.NoError(t, err)
+					types = append(types, token.Type)
+				}
+				assert.Equal(t, input.expect, types)
+			})
+		}
+
+		t.Run("delimiterTerminatedNumberIsDecoded", func(t *testing.T) {
+			t.Parallel()
+
+			s := interpret.NewScanner(strings.NewReader("[1 2 3]"))
+			intValues := []int64{}
+			for {
+				token, err := s.NextToken()
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				assert.NoError(t, err)
+				if token.Type == interpret.INT_TOKEN {
+					intValues = append(intValues, token.IntValue)
+				}
+			}
+			assert.Equal(t, []int64{1, 2, 3}, intValues)
+		})
+	})
+
+	t.Run("position", func(t *testing.T) {
+		t.Parallel()
+
+		s := interpret.NewScanner(strings.NewReader("abc\ndef"))
+
+		first, err := s.NextToken()
+		assert.NoError(t, err)
+		assert.Equal(t, runes.Pos{Line: 1, Col: 1, Offset: 0}, first.Pos)
+
+		second, err := s.NextToken()
+		assert.NoError(t, err)
+		assert.Equal(t, runes.Pos{Line: 2, Col: 1, Offset: 4}, second.Pos)
+	})
+
+	t.Run("errorHandler", func(t *testing.T) {
+		t.Parallel()
+
+		s := interpret.NewScanner(strings.NewReader("<XY> good <ZQ>"))
+
+		var reported []*interpret.ScanError
+		s.SetErrorHandler(func(pos runes.Pos, err *interpret.ScanError) {
+			reported = append(reported, err)
+		})
+
+		tokens := []interpret.Token{}
+		for {
+			token, err := s.NextToken()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			assert.NoError(t, err)
+			tokens = append(tokens, token)
+		}
+
+		assert.Len(t, reported, 2)
+		assert.Len(t, tokens, 1)
+		assert.Equal(t, interpret.NAME_TOKEN, tokens[0].Type)
+		assert.Equal(t, "good", string(tokens[0].Value))
+	})
+
 	t.Run("all", func(t *testing.T) {
 		t.Parallel()
 
@@ -187,12 +450,13 @@ myNegativeReal -3.1456
 
 		s := interpret.NewScanner(strings.NewReader(input))
 		tokens, errs := iterator.Collect2(s.Tokens())
-		assert.False(t, array.Some(errs, func(err error) bool {
+		assert.Equal(t, -1, array.Some(errs, func(err error) bool {
 			return err != nil
 		}))
 		assert.Len(t, tokens, len(expect))
-		assert.Equal(t, expect, tokens)
-
-		// t.Log(tokens)
+		for i, token := range tokens {
+			assert.Equal(t, expect[i].Type, token.Type)
+			assert.Equal(t, expect[i].Value, token.Value)
+		}
 	})
 }
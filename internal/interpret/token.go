@@ -1,21 +1,36 @@
 package interpret
 
+import "github.com/ian-shakespeare/libps/pkg/runes"
+
 type TokenType int
 
 const (
-	UNKNOWN_TOKEN       TokenType = 0
-	INT_TOKEN           TokenType = 1
-	REAL_TOKEN          TokenType = 2
-	RADIX_TOKEN         TokenType = 3
-	LIT_STRING_TOKEN    TokenType = 4
-	HEX_STRING_TOKEN    TokenType = 5
-	BASE85_STRING_TOKEN TokenType = 6
-	NAME_TOKEN          TokenType = 7
+	UNKNOWN_TOKEN        TokenType = 0
+	INT_TOKEN            TokenType = 1
+	REAL_TOKEN           TokenType = 2
+	RADIX_TOKEN          TokenType = 3
+	STRING_TOKEN         TokenType = 4
+	NAME_TOKEN           TokenType = 5
+	LIT_NAME_TOKEN       TokenType = 6
+	IMMEDIATE_NAME_TOKEN TokenType = 7
+	PROC_BEGIN_TOKEN     TokenType = 8
+	PROC_END_TOKEN       TokenType = 9
+	ARRAY_BEGIN_TOKEN    TokenType = 10
+	ARRAY_END_TOKEN      TokenType = 11
 )
 
 type Token struct {
-	Type  TokenType
+	Type TokenType
+	// Value holds the token's raw runes as scanned, except that
+	// LIT_NAME_TOKEN and IMMEDIATE_NAME_TOKEN omit their leading slashes;
+	// for STRING_TOKEN it has already been decoded to the string's bytes.
 	Value []rune
+	Pos   runes.Pos
+	// IntValue and FloatValue hold the decoded numeric value of an
+	// INT_TOKEN/RADIX_TOKEN or a REAL_TOKEN, respectively, so consumers
+	// do not need to re-parse Value.
+	IntValue   int64
+	FloatValue float64
 }
 
 func (t *Token) Append(char ...rune) {
@@ -7,12 +7,89 @@ import (
 	"unicode/utf8"
 )
 
+// Pos identifies a location within a rune stream.
+type Pos struct {
+	Line   int
+	Col    int
+	Offset int
+}
+
 type Reader struct {
 	*bufio.Reader
+	line          int
+	col           int
+	offset        int
+	pendingCR     bool
+	prevPos       Pos
+	prevPendingCR bool
 }
 
 func NewReader(r io.Reader) *Reader {
-	return &Reader{bufio.NewReader(r)}
+	return &Reader{
+		Reader: bufio.NewReader(r),
+		line:   1,
+		col:    1,
+	}
+}
+
+// Pos returns the position of the next rune to be read.
+func (r *Reader) Pos() Pos {
+	return Pos{Line: r.line, Col: r.col, Offset: r.offset}
+}
+
+// ReadRune reads the next rune, updating the reader's line/column/offset
+// tracking. A lone '\r', a lone '\n', and a "\r\n" pair each count as a
+// single line break, matching the PostScript end-of-line rule; '\f' is
+// treated as a line break as well.
+func (r *Reader) ReadRune() (rune, int, error) {
+	prevPos := r.Pos()
+	prevPendingCR := r.pendingCR
+
+	char, size, err := r.Reader.ReadRune()
+	if err != nil {
+		return char, size, err
+	}
+
+	r.prevPos = prevPos
+	r.prevPendingCR = prevPendingCR
+	r.offset += size
+
+	switch char {
+	case '\r':
+		r.line++
+		r.col = 1
+		r.pendingCR = true
+	case '\n':
+		if r.pendingCR {
+			r.pendingCR = false
+		} else {
+			r.line++
+			r.col = 1
+		}
+	case '\f':
+		r.line++
+		r.col = 1
+		r.pendingCR = false
+	default:
+		r.col++
+		r.pendingCR = false
+	}
+
+	return char, size, nil
+}
+
+// UnreadRune undoes the most recent ReadRune call, restoring the reader's
+// position along with it. As with bufio.Reader, only a single pending
+// ReadRune may be unread.
+func (r *Reader) UnreadRune() error {
+	if err := r.Reader.UnreadRune(); err != nil {
+		return err
+	}
+
+	r.line, r.col, r.offset = r.prevPos.Line, r.prevPos.Col, r.prevPos.Offset
+	r.pendingCR = r.prevPendingCR
+
+	return nil
 }
 
 func (r *Reader) PeekRunes(n int) ([]rune, error) {